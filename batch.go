@@ -0,0 +1,79 @@
+package irc
+
+// Batch is the result of reassembling an IRCv3 BATCH: the messages
+// tagged with a batch's reference, collected in the order they were
+// received, along with the type and parameters the batch was opened
+// with (e.g. "chathistory #channel" for a chathistory playback).
+type Batch struct {
+	Type     string
+	Params   []string
+	Messages []Message
+}
+
+// BatchReader wraps a Decoder and reassembles IRCv3 batches, so
+// callers can consume history playback and chathistory bundles as a
+// single value instead of reassembling an interleaved stream of
+// batch-tagged Messages themselves.
+type BatchReader struct {
+	dec     *Decoder
+	batches map[string]*Batch
+}
+
+// NewBatchReader returns a BatchReader that reads messages from dec.
+func NewBatchReader(dec *Decoder) *BatchReader {
+	return &BatchReader{
+		dec:     dec,
+		batches: make(map[string]*Batch),
+	}
+}
+
+// Next reads messages from the underlying Decoder until it can return
+// either the next fully-assembled Batch, or the next Message that
+// does not belong to any batch. Exactly one of the two return values
+// is non-nil unless err is non-nil.
+func (r *BatchReader) Next() (*Batch, *Message, error) {
+	for {
+		var m Message
+		if err := r.dec.Decode(&m); err != nil {
+			return nil, nil, err
+		}
+
+		if m.Command == BATCH && len(m.Params) >= 1 {
+			if ref, ok := batchReference(m.Params[0], '+'); ok {
+				b := &Batch{}
+				if len(m.Params) >= 2 {
+					b.Type = m.Params[1]
+				}
+				if len(m.Params) > 2 {
+					b.Params = m.Params[2:]
+				}
+				r.batches[ref] = b
+				continue
+			}
+			if ref, ok := batchReference(m.Params[0], '-'); ok {
+				b, ok := r.batches[ref]
+				if !ok {
+					continue
+				}
+				delete(r.batches, ref)
+				return b, nil, nil
+			}
+		}
+
+		if ref, ok := m.Tags.Batch(); ok {
+			if b, ok := r.batches[ref]; ok {
+				b.Messages = append(b.Messages, m)
+				continue
+			}
+		}
+
+		return nil, &m, nil
+	}
+}
+
+func batchReference(param string, sigil byte) (string, bool) {
+	if len(param) == 0 || param[0] != sigil {
+		return "", false
+	}
+	return param[1:], true
+}
@@ -0,0 +1,46 @@
+package irc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBatchReader(t *testing.T) {
+	t.Parallel()
+	input := "" +
+		"@batch=abc :irc.example.com BATCH +abc chathistory #chan\r\n" +
+		"@batch=abc :alice PRIVMSG #chan :hello\r\n" +
+		"@batch=abc :bob PRIVMSG #chan :hi\r\n" +
+		":irc.example.com BATCH -abc\r\n" +
+		":carol PRIVMSG #chan :not batched\r\n"
+
+	br := NewBatchReader(NewDecoder(strings.NewReader(input)))
+
+	b, m, err := br.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m != nil {
+		t.Fatalf("expected a batch, got message %#v", m)
+	}
+	if b.Type != "chathistory" || len(b.Params) != 1 || b.Params[0] != "#chan" {
+		t.Fatalf("unexpected batch header: %#v", b)
+	}
+	if len(b.Messages) != 2 {
+		t.Fatalf("expected 2 messages, got %d", len(b.Messages))
+	}
+	if b.Messages[0].Prefix.Name != "alice" || b.Messages[1].Prefix.Name != "bob" {
+		t.Fatalf("unexpected batch messages: %#v", b.Messages)
+	}
+
+	b, m, err = br.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if b != nil {
+		t.Fatalf("expected a message, got batch %#v", b)
+	}
+	if m.Prefix.Name != "carol" {
+		t.Fatalf("unexpected message: %#v", m)
+	}
+}
@@ -0,0 +1,47 @@
+package irc
+
+import "testing"
+
+func TestCommandIsNumeric(t *testing.T) {
+	t.Parallel()
+	if !RPL_WELCOME.IsNumeric() {
+		t.Fatal("expected RPL_WELCOME to be numeric")
+	}
+	if PRIVMSG.IsNumeric() {
+		t.Fatal("expected PRIVMSG to not be numeric")
+	}
+}
+
+func TestCommandNumeric(t *testing.T) {
+	t.Parallel()
+	n, ok := RPL_WELCOME.Numeric()
+	if !ok || n != 1 {
+		t.Fatalf("expected (1, true), got (%d, %v)", n, ok)
+	}
+	if _, ok := PRIVMSG.Numeric(); ok {
+		t.Fatal("expected !ok")
+	}
+}
+
+func TestCommandString(t *testing.T) {
+	t.Parallel()
+	if got, want := RPL_WELCOME.String(), "RPL_WELCOME"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := PRIVMSG.String(), "PRIVMSG"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	if got, want := Command("999").String(), "999"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestMessageCommandDispatch(t *testing.T) {
+	t.Parallel()
+	m := ParseString(":irc.example.com 001 nick :Welcome\r\n")
+	switch m.Command {
+	case RPL_WELCOME:
+	default:
+		t.Fatalf("expected RPL_WELCOME, got %v", m.Command)
+	}
+}
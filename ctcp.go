@@ -0,0 +1,134 @@
+package irc
+
+import "strings"
+
+// ctcpDelim is the byte CTCP uses to mark the start and end of an
+// extended message embedded in a PRIVMSG or NOTICE.
+const ctcpDelim = '\x01'
+
+// CTCP is a single CTCP extended message, e.g. the ACTION, VERSION or
+// PING commands exchanged inside a PRIVMSG or NOTICE body.
+type CTCP struct {
+	Tag  string
+	Data string
+}
+
+// ParseCTCP extracts the CTCP messages embedded in params, the
+// trailing parameter of a PRIVMSG or NOTICE, along with any text
+// outside of the CTCP delimiters. ok is false if params carried no
+// CTCP messages.
+func ParseCTCP(params []string) (ctcps []CTCP, text string, ok bool) {
+	if len(params) == 0 {
+		return nil, "", false
+	}
+
+	body := params[len(params)-1]
+	var out strings.Builder
+	for len(body) > 0 {
+		i := strings.IndexByte(body, ctcpDelim)
+		if i < 0 {
+			out.WriteString(body)
+			break
+		}
+		out.WriteString(body[:i])
+		body = body[i+1:]
+
+		j := strings.IndexByte(body, ctcpDelim)
+		var raw string
+		if j < 0 {
+			raw, body = body, ""
+		} else {
+			raw, body = body[:j], body[j+1:]
+		}
+
+		raw = unquoteCTCP(raw)
+		tag, data := raw, ""
+		if k := strings.IndexByte(raw, ' '); k >= 0 {
+			tag, data = raw[:k], raw[k+1:]
+		}
+		if tag != "" {
+			ctcps = append(ctcps, CTCP{Tag: tag, Data: data})
+		}
+	}
+
+	return ctcps, out.String(), len(ctcps) != 0
+}
+
+// CTCP returns the CTCP messages embedded in m's body, if m is a
+// PRIVMSG or NOTICE carrying any.
+func (m *Message) CTCP() ([]CTCP, bool) {
+	if m.Command != PRIVMSG && m.Command != NOTICE {
+		return nil, false
+	}
+	ctcps, _, ok := ParseCTCP(m.Params)
+	return ctcps, ok
+}
+
+// NewCTCP returns a PRIVMSG addressed to target carrying a single
+// CTCP extended message with the given tag and data.
+func NewCTCP(target, tag, data string) Message {
+	var body strings.Builder
+	body.WriteByte(ctcpDelim)
+	body.WriteString(tag)
+	if data != "" {
+		body.WriteByte(' ')
+		body.WriteString(quoteCTCP(data))
+	}
+	body.WriteByte(ctcpDelim)
+
+	return Message{
+		Command: PRIVMSG,
+		Params:  []string{target, body.String()},
+	}
+}
+
+// quoteCTCP applies CTCP low-level ("M-QUOTE") escaping, which keeps
+// NUL, CR and LF out of the wire form by replacing each of them, and
+// the \x10 quote byte itself, with a two-byte \x10 sequence.
+func quoteCTCP(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 0:
+			b.WriteByte('\x10')
+			b.WriteByte('0')
+		case '\n':
+			b.WriteByte('\x10')
+			b.WriteByte('n')
+		case '\r':
+			b.WriteByte('\x10')
+			b.WriteByte('r')
+		case '\x10':
+			b.WriteByte('\x10')
+			b.WriteByte('\x10')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// unquoteCTCP reverses quoteCTCP.
+func unquoteCTCP(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\x10' || i+1 >= len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case '0':
+			b.WriteByte(0)
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case '\x10':
+			b.WriteByte('\x10')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
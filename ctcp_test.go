@@ -0,0 +1,88 @@
+package irc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCTCP(t *testing.T) {
+	t.Parallel()
+	ctcps, text, ok := ParseCTCP([]string{"#chan", "\x01ACTION waves\x01"})
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if text != "" {
+		t.Fatalf("expected empty text, got %q", text)
+	}
+	want := []CTCP{{Tag: "ACTION", Data: "waves"}}
+	if !reflect.DeepEqual(ctcps, want) {
+		t.Fatalf("expected %#v, got %#v", want, ctcps)
+	}
+}
+
+func TestParseCTCPMixedText(t *testing.T) {
+	t.Parallel()
+	ctcps, text, ok := ParseCTCP([]string{"#chan", "hi \x01VERSION\x01 there"})
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if text != "hi  there" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+	want := []CTCP{{Tag: "VERSION"}}
+	if !reflect.DeepEqual(ctcps, want) {
+		t.Fatalf("expected %#v, got %#v", want, ctcps)
+	}
+}
+
+func TestParseCTCPNone(t *testing.T) {
+	t.Parallel()
+	ctcps, text, ok := ParseCTCP([]string{"#chan", "just text"})
+	if ok || ctcps != nil {
+		t.Fatalf("expected no CTCP, got %#v", ctcps)
+	}
+	if text != "just text" {
+		t.Fatalf("unexpected text: %q", text)
+	}
+}
+
+func TestMessageCTCP(t *testing.T) {
+	t.Parallel()
+	m := ParseString(":alice PRIVMSG bob :\x01PING 12345\x01")
+	ctcps, ok := m.CTCP()
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := []CTCP{{Tag: "PING", Data: "12345"}}
+	if !reflect.DeepEqual(ctcps, want) {
+		t.Fatalf("expected %#v, got %#v", want, ctcps)
+	}
+}
+
+func TestNewCTCP(t *testing.T) {
+	t.Parallel()
+	m := NewCTCP("#chan", "ACTION", "waves")
+	if m.Command != "PRIVMSG" || m.Params[0] != "#chan" {
+		t.Fatalf("unexpected message: %#v", m)
+	}
+	ctcps, ok := m.CTCP()
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	want := []CTCP{{Tag: "ACTION", Data: "waves"}}
+	if !reflect.DeepEqual(ctcps, want) {
+		t.Fatalf("expected %#v, got %#v", want, ctcps)
+	}
+}
+
+func TestCTCPQuoting(t *testing.T) {
+	t.Parallel()
+	m := NewCTCP("#chan", "PING", "has\x10a byte\r\nand newline")
+	ctcps, ok := m.CTCP()
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if got, want := ctcps[0].Data, "has\x10a byte\r\nand newline"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,122 @@
+package irc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+)
+
+// DefaultMaxLineLength is the maximum size, in bytes, of a single IRC
+// line (including tags, prefix, command, params and the trailing
+// CRLF) that a Decoder will accept before returning ErrLineTooLong.
+const DefaultMaxLineLength = 8192
+
+// ErrLineTooLong is returned by Decoder.Decode when a line exceeds the
+// decoder's configured maximum line length.
+var ErrLineTooLong = errors.New("irc: line too long")
+
+// ScanIRCLine is a bufio.SplitFunc that splits an input stream into
+// CRLF-terminated IRC lines, with the terminator stripped from the
+// returned token. A bare LF is also accepted as a terminator, since
+// some servers and most test fixtures omit the CR. It can be used
+// directly with a bufio.Scanner to frame IRC messages read from e.g. a
+// net.Conn.
+func ScanIRCLine(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		end := i
+		if end > 0 && data[end-1] == '\r' {
+			end--
+		}
+		return i + 1, data[:end], nil
+	}
+
+	if atEOF {
+		return len(data), data, nil
+	}
+
+	return 0, nil, nil
+}
+
+// Decoder reads Messages from an io.Reader, handling messages that
+// arrive split across multiple reads (as is common over a net.Conn).
+type Decoder struct {
+	scanner *bufio.Scanner
+	err     error
+}
+
+// NewDecoder returns a new Decoder that reads from r. The decoder's
+// maximum line length starts at DefaultMaxLineLength; use
+// SetMaxLineLength to change it before the first call to Decode.
+func NewDecoder(r io.Reader) *Decoder {
+	d := &Decoder{scanner: bufio.NewScanner(r)}
+	d.scanner.Split(ScanIRCLine)
+	d.SetMaxLineLength(DefaultMaxLineLength)
+	return d
+}
+
+// SetMaxLineLength sets the maximum line length the Decoder will
+// accept. It must be called before the first call to Decode.
+func (d *Decoder) SetMaxLineLength(n int) {
+	d.scanner.Buffer(make([]byte, 0, n), n)
+}
+
+// Decode reads the next message from the underlying reader and stores
+// it in m, reusing m.Tags and m.Params where possible. It returns
+// io.EOF once the stream ends cleanly, or ErrLineTooLong if a line
+// exceeds the configured maximum length. Once Decode has returned an
+// error, the Decoder is done: the underlying bufio.Scanner never
+// recovers from a failed Scan, so Decode keeps returning the same
+// error on every subsequent call rather than feeding it whatever
+// partial line the scanner left behind.
+func (d *Decoder) Decode(m *Message) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	for {
+		if !d.scanner.Scan() {
+			if err := d.scanner.Err(); err != nil {
+				if errors.Is(err, bufio.ErrTooLong) {
+					d.err = ErrLineTooLong
+				} else {
+					d.err = err
+				}
+			} else {
+				d.err = io.EOF
+			}
+			return d.err
+		}
+
+		line := d.scanner.Bytes()
+		if len(line) == 0 {
+			// IRC servers occasionally send empty lines as a
+			// keepalive; skip them rather than yielding an
+			// empty Message.
+			continue
+		}
+
+		_, err := ParseInto(line, m)
+		return err
+	}
+}
+
+// Encoder writes Messages to an io.Writer.
+type Encoder struct {
+	w io.Writer
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes m to the underlying writer.
+func (e *Encoder) Encode(m Message) error {
+	_, err := e.w.Write(m.Bytes())
+	return err
+}
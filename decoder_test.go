@@ -0,0 +1,134 @@
+package irc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	r := strings.NewReader(":coolguy PRIVMSG bar :hello\r\nFOO bar baz\r\n")
+	dec := NewDecoder(r)
+
+	var m Message
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Command != "PRIVMSG" || m.Prefix.Name != "coolguy" {
+		t.Fatalf("unexpected message: %#v", m)
+	}
+
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Command != "FOO" {
+		t.Fatalf("unexpected message: %#v", m)
+	}
+
+	if err := dec.Decode(&m); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestDecoderSplitAcrossReads(t *testing.T) {
+	t.Parallel()
+	pr, pw := io.Pipe()
+	dec := NewDecoder(pr)
+
+	go func() {
+		pw.Write([]byte(":cool"))
+		pw.Write([]byte("guy PRIVMSG bar "))
+		pw.Write([]byte(":hello\r\n"))
+		pw.Close()
+	}()
+
+	var m Message
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if m.Prefix.Name != "coolguy" || m.Params[1] != "hello" {
+		t.Fatalf("unexpected message: %#v", m)
+	}
+}
+
+func TestDecoderLineTooLong(t *testing.T) {
+	t.Parallel()
+	r := strings.NewReader(strings.Repeat("a", 100) + "\r\n")
+	dec := NewDecoder(r)
+	dec.SetMaxLineLength(10)
+
+	var m Message
+	if err := dec.Decode(&m); !errors.Is(err, ErrLineTooLong) {
+		t.Fatalf("expected ErrLineTooLong, got %v", err)
+	}
+}
+
+func TestDecoderLineTooLongIsSticky(t *testing.T) {
+	t.Parallel()
+	r := strings.NewReader(strings.Repeat("a", 100) + "\r\n" + "FOO bar\r\n")
+	dec := NewDecoder(r)
+	dec.SetMaxLineLength(10)
+
+	var m Message
+	if err := dec.Decode(&m); !errors.Is(err, ErrLineTooLong) {
+		t.Fatalf("expected ErrLineTooLong, got %v", err)
+	}
+
+	// Once a line is too long, the underlying scanner never
+	// recovers, so every subsequent Decode must keep returning the
+	// same error rather than a bogus Message built from leftover
+	// bytes.
+	for i := 0; i < 3; i++ {
+		if err := dec.Decode(&m); !errors.Is(err, ErrLineTooLong) {
+			t.Fatalf("expected ErrLineTooLong on repeat call, got %v (m=%#v)", err, m)
+		}
+	}
+}
+
+func TestDecoderReusesStorage(t *testing.T) {
+	t.Parallel()
+	r := strings.NewReader("@a=1;b=2 :src PRIVMSG #chan :hi\r\nFOO bar baz\r\n")
+	dec := NewDecoder(r)
+
+	var m Message
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags, params := m.Tags, m.Params[:cap(m.Params)]
+
+	if err := dec.Decode(&m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(m.Tags) != 0 {
+		t.Fatalf("expected tags to be cleared, got %#v", m.Tags)
+	}
+	if reflect.ValueOf(m.Tags).Pointer() != reflect.ValueOf(tags).Pointer() {
+		t.Fatal("expected Tags map to be reused, not reallocated")
+	}
+	if reflect.ValueOf(m.Params[:cap(m.Params)]).Pointer() != reflect.ValueOf(params).Pointer() {
+		t.Fatal("expected Params backing array to be reused, not reallocated")
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+
+	if err := enc.Encode(Message{
+		Prefix:  Prefix{Name: "coolguy"},
+		Command: "PRIVMSG",
+		Params:  []string{"bar", "hello"},
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := buf.String(), ":coolguy PRIVMSG bar hello\r\n"; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
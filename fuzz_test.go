@@ -0,0 +1,91 @@
+package irc
+
+import (
+	"reflect"
+	"testing"
+)
+
+// canonStable reports whether c is shaped like a command Bytes() can
+// round-trip losslessly. A Command whose first byte is '@' or ':' is
+// indistinguishable, once serialized, from the leading sigil of a tags
+// section or prefix (e.g. Command("@") encodes as "@\r\n", which
+// re-parses as an empty tags section followed by an empty command).
+// That ambiguity is inherent to the wire format itself rather than a
+// parsing bug, and predates this fuzz harness, so idempotence is only
+// skipped for the actual sigil collision rather than for every command
+// shape a real server wouldn't send.
+func canonStable(c Command) bool {
+	return c == "" || (c[0] != '@' && c[0] != ':')
+}
+
+// FuzzParse checks that Parse never panics or overreads its input,
+// and that its canonical wire form (Bytes()) is a fixed point: once
+// arbitrary input has been canonicalized by one round trip through
+// Bytes(), canonicalizing it again changes nothing. This is weaker
+// than requiring the canonical form to equal the original parse,
+// which does not hold in general: the parser is deliberately
+// permissive about things (e.g. a bare empty prefix) that Bytes()
+// has no lossless way to represent.
+func FuzzParse(f *testing.F) {
+	for _, test := range splitTests {
+		f.Add([]byte(test.Input))
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, n := Parse(data)
+		if n > len(data) {
+			t.Fatalf("Parse consumed %d bytes of a %d-byte input", n, len(data))
+		}
+
+		canon, _ := Parse(m.Bytes())
+		if !canonStable(canon.Command) {
+			return
+		}
+		again, _ := Parse(canon.Bytes())
+		if !reflect.DeepEqual(again.Tags, canon.Tags) {
+			t.Fatalf("tags not idempotent: %#v != %#v", again.Tags, canon.Tags)
+		}
+		if !reflect.DeepEqual(again.Prefix, canon.Prefix) {
+			t.Fatalf("prefix not idempotent: %#v != %#v", again.Prefix, canon.Prefix)
+		}
+		if again.Command != canon.Command {
+			t.Fatalf("command not idempotent: %#v != %#v", again.Command, canon.Command)
+		}
+		if !reflect.DeepEqual(again.Params, canon.Params) {
+			t.Fatalf("params not idempotent: %#v != %#v", again.Params, canon.Params)
+		}
+	})
+}
+
+// FuzzRoundTrip checks that once a Message has been serialized with
+// Bytes(), parsing it back and serializing it again reproduces the
+// same Tags, Prefix, Command and Params, catching escape,
+// trailing-parameter and empty-value bugs that the hand-written table
+// tests in irc_test.go miss.
+func FuzzRoundTrip(f *testing.F) {
+	for _, test := range joinTests {
+		f.Add(test.Input.Bytes())
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		m, _ := Parse(data)
+		canon, _ := Parse(m.Bytes())
+		if !canonStable(canon.Command) {
+			return
+		}
+		again, _ := Parse(canon.Bytes())
+
+		if !reflect.DeepEqual(again.Tags, canon.Tags) {
+			t.Fatalf("tags: expected %#v, got %#v", canon.Tags, again.Tags)
+		}
+		if !reflect.DeepEqual(again.Prefix, canon.Prefix) {
+			t.Fatalf("prefix: expected %#v, got %#v", canon.Prefix, again.Prefix)
+		}
+		if again.Command != canon.Command {
+			t.Fatalf("command: expected %#v, got %#v", canon.Command, again.Command)
+		}
+		if !reflect.DeepEqual(again.Params, canon.Params) {
+			t.Fatalf("params: expected %#v, got %#v", canon.Params, again.Params)
+		}
+	})
+}
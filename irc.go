@@ -77,7 +77,7 @@ func (p Prefix) String() string {
 type Message struct {
 	Tags
 	Prefix
-	Command string
+	Command Command
 	Params  []string
 }
 
@@ -96,7 +96,7 @@ func (m Message) Bytes() []byte {
 		buf.WriteByte(' ')
 	}
 
-	buf.WriteString(m.Command)
+	buf.WriteString(string(m.Command))
 
 	for i, param := range m.Params {
 		buf.WriteByte(' ')
@@ -117,84 +117,119 @@ func (m Message) String() string {
 	return string(m.Bytes())
 }
 
-func parseTags(p []byte) (Tags, int) {
+// parseTagsInto parses the IRCv3 tags prefix of p into tags, which the
+// caller is expected to have already cleared, and returns how much of
+// p it consumed. Tag values without a backslash take a fast path that
+// slices directly into p; values requiring escape processing fall
+// back to a builder drawn from tagValueBuilderPool.
+func parseTagsInto(p []byte, tags Tags) int {
 	const (
 		stKey = iota
 		stValue
 		stEscape
 	)
 
-	tags := Tags{}
 	i := 0
-
 	if len(p) == 0 || p[i] != '@' {
-		return tags, i
+		return i
 	}
 	i++
 
-	var key, value strings.Builder
+	keyStart, keyEnd := i, i
+	valueStart := i
+	hasValue := false
+	var valueBuf *strings.Builder
 	st := stKey
-	for _, b := range p[i:] {
-		i++
+
+	commit := func(end int) {
+		if keyEnd <= keyStart {
+			return
+		}
+		key := string(p[keyStart:keyEnd])
+		switch {
+		case valueBuf != nil:
+			tags[key] = valueBuf.String()
+			putTagValueBuilder(valueBuf)
+			valueBuf = nil
+		case hasValue:
+			tags[key] = string(p[valueStart:end])
+		default:
+			tags[key] = ""
+		}
+	}
+
+	for i < len(p) {
+		b := p[i]
 		switch b {
 		case ' ':
-			if key.Len() != 0 {
-				tags[key.String()] = value.String()
-			}
-			return tags, i
+			commit(i)
+			return i + 1
 		case '\r', '\n':
-			if key.Len() != 0 {
-				tags[key.String()] = value.String()
-			}
-			return tags, i - 1
+			commit(i)
+			return i
 		case ';':
-			if key.Len() != 0 {
-				tags[key.String()] = value.String()
-			}
-			key.Reset()
-			value.Reset()
+			commit(i)
+			i++
+			keyStart, keyEnd = i, i
+			valueStart = i
+			hasValue = false
 			st = stKey
+			continue
 		case '=':
-			st = stValue
-		default:
-			switch {
-			case st == stKey:
-				key.WriteByte(b)
-			case st == stValue && b == '\\':
-				st = stEscape
-			case st == stValue:
-				value.WriteByte(b)
-			case st == stEscape && b == ':':
-				value.WriteByte(';')
-				st = stValue
-			case st == stEscape && b == 's':
-				value.WriteByte(' ')
-				st = stValue
-			case st == stEscape && b == '\\':
-				value.WriteByte('\\')
-				st = stValue
-			case st == stEscape && b == 'r':
-				value.WriteByte('\r')
-				st = stValue
-			case st == stEscape && b == 'n':
-				value.WriteByte('\n')
-				st = stValue
-			case st == stEscape:
-				value.WriteByte(b)
+			if st == stKey {
+				keyEnd = i
 				st = stValue
+				valueStart = i + 1
+				hasValue = true
 			}
+			i++
+			continue
 		}
-	}
 
-	if key.Len() != 0 {
-		tags[key.String()] = value.String()
+		switch st {
+		case stKey:
+			keyEnd = i + 1
+		case stValue:
+			if b == '\\' {
+				if valueBuf == nil {
+					valueBuf = getTagValueBuilder()
+					valueBuf.Write(p[valueStart:i])
+				}
+				st = stEscape
+			} else if valueBuf != nil {
+				valueBuf.WriteByte(b)
+			}
+		case stEscape:
+			var out byte
+			switch b {
+			case ':':
+				out = ';'
+			case 's':
+				out = ' '
+			case '\\':
+				out = '\\'
+			case 'r':
+				out = '\r'
+			case 'n':
+				out = '\n'
+			default:
+				out = b
+			}
+			valueBuf.WriteByte(out)
+			st = stValue
+		}
+		i++
 	}
 
-	return tags, i
+	commit(i)
+	return i
 }
 
+// parsePrefix parses an IRC prefix from p and returns it, along with
+// how much of p it consumed. Prefix components contain no escape
+// sequences, so they are always sliced directly out of p.
 func parsePrefix(p []byte) (Prefix, int) {
-	prefix := Prefix{}
+	var prefix Prefix
 	i := 0
 
 	if len(p) == 0 || p[i] != ':' {
@@ -202,169 +237,182 @@ func parsePrefix(p []byte) (Prefix, int) {
 	}
 	i++
 
-	var name strings.Builder
-nameloop:
-	for _, b := range p[i:] {
-		i++
-		switch b {
-		case '!':
-			prefix.Name = name.String()
-			i--
-			break nameloop
-		case '@':
-			prefix.Name = name.String()
-			i--
-			break nameloop
+	nameStart := i
+	for i < len(p) {
+		switch p[i] {
+		case '!', '@':
+			prefix.Name = string(p[nameStart:i])
+			goto user
 		case ' ':
-			prefix.Name = name.String()
-			return prefix, i
+			prefix.Name = string(p[nameStart:i])
+			return prefix, i + 1
 		case '\r', '\n':
-			prefix.Name = name.String()
-			return prefix, i - 1
-		default:
-			name.WriteByte(b)
+			prefix.Name = string(p[nameStart:i])
+			return prefix, i
 		}
+		i++
 	}
-	prefix.Name = name.String()
+	prefix.Name = string(p[nameStart:i])
+	return prefix, i
 
-	if i < len(p) && p[i] == '!' {
+user:
+	if p[i] == '!' {
 		i++
-		var user strings.Builder
-	userloop:
-		for _, b := range p[i:] {
-			i++
-			switch b {
+		userStart := i
+		for i < len(p) {
+			switch p[i] {
 			case '@':
-				i--
-				prefix.User = user.String()
-				break userloop
+				prefix.User = string(p[userStart:i])
+				goto host
 			case ' ':
-				prefix.User = user.String()
-				return prefix, i
+				prefix.User = string(p[userStart:i])
+				return prefix, i + 1
 			case '\r', '\n':
-				prefix.User = user.String()
-				return prefix, i - 1
-			default:
-				user.WriteByte(b)
+				prefix.User = string(p[userStart:i])
+				return prefix, i
 			}
+			i++
 		}
-		prefix.User = user.String()
+		prefix.User = string(p[userStart:i])
+		return prefix, i
 	}
 
+host:
 	if i < len(p) && p[i] == '@' {
 		i++
-		var host strings.Builder
-		for _, b := range p[i:] {
-			i++
-			switch b {
+		hostStart := i
+		for i < len(p) {
+			switch p[i] {
 			case ' ':
-				prefix.Host = host.String()
-				return prefix, i
+				prefix.Host = string(p[hostStart:i])
+				return prefix, i + 1
 			case '\r', '\n':
-				prefix.Host = host.String()
-				return prefix, i - 1
-			default:
-				host.WriteByte(b)
+				prefix.Host = string(p[hostStart:i])
+				return prefix, i
 			}
+			i++
 		}
-		prefix.Host = host.String()
+		prefix.Host = string(p[hostStart:i])
 	}
 
 	return prefix, i
 }
 
-func parseCommand(p []byte) (string, int) {
-	var command strings.Builder
+// parseCommand parses a command name from p and returns it, along
+// with how much of p it consumed. Commands already sent in uppercase
+// (the common case) are sliced directly out of p; mixed-case commands
+// fall back to a builder to fold them to uppercase.
+func parseCommand(p []byte) (Command, int) {
 	i := 0
+	var buf *strings.Builder
 
-	for _, b := range p[i:] {
-		i++
+	for i < len(p) {
+		b := p[i]
 		switch b {
 		case ' ':
-			return command.String(), i
+			return commandFrom(p, i, buf), i + 1
 		case '\r', '\n':
-			return command.String(), i - 1
-		default:
-			if 'a' <= b && b <= 'z' {
-				b -= 'a' - 'A'
+			return commandFrom(p, i, buf), i
+		}
+		if 'a' <= b && b <= 'z' {
+			if buf == nil {
+				buf = getTagValueBuilder()
+				buf.Write(p[:i])
 			}
-			command.WriteByte(b)
+			buf.WriteByte(b - ('a' - 'A'))
+		} else if buf != nil {
+			buf.WriteByte(b)
 		}
+		i++
 	}
 
-	return command.String(), i
+	return commandFrom(p, i, buf), i
+}
+
+func commandFrom(p []byte, end int, buf *strings.Builder) Command {
+	if buf != nil {
+		c := Command(buf.String())
+		putTagValueBuilder(buf)
+		return c
+	}
+	return Command(p[:end])
 }
 
-func parseParams(p []byte) ([]string, int) {
-	params := []string{}
+// parseParamsInto parses the trailing params of p into params (reused
+// from the caller, truncated to length 0), and returns the resulting
+// slice along with how much of p it consumed. Params contain no
+// escape sequences, so they are always sliced directly out of p.
+func parseParamsInto(p []byte, params []string) ([]string, int) {
 	i := 0
 
-	var param strings.Builder
-	trailing := false
-loop:
-	for _, b := range p[i:] {
-		i++
-		switch b {
-		case ' ':
-			if param.Len() != 0 {
-				params = append(params, param.String())
-				param.Reset()
-			}
-		case '\r', '\n':
-			if param.Len() != 0 {
-				params = append(params, param.String())
-				param.Reset()
-			}
-			return params, i - 1
-		case ':':
-			if param.Len() == 0 {
-				trailing = true
-				break loop
-			}
-		default:
-			param.WriteByte(b)
+	for i < len(p) {
+		if p[i] == '\r' || p[i] == '\n' {
+			return params, i
 		}
-	}
-
-	if trailing {
-		for _, b := range p[i:] {
+		if p[i] == ' ' {
 			i++
-			switch b {
-			case '\r', '\n':
-				return append(params, param.String()), i - 1
-			default:
-				param.WriteByte(b)
+			continue
+		}
+		if p[i] == ':' {
+			i++
+			start := i
+			for i < len(p) && p[i] != '\r' && p[i] != '\n' {
+				i++
 			}
+			return append(params, string(p[start:i])), i
 		}
 
-		return append(params, param.String()), i
-	}
-
-	if param.Len() != 0 {
-		params = append(params, param.String())
+		start := i
+		for i < len(p) && p[i] != ' ' && p[i] != '\r' && p[i] != '\n' {
+			i++
+		}
+		params = append(params, string(p[start:i]))
 	}
 
 	return params, i
-
 }
 
-// Parse parses an IRC message from p and returns it,
-// along with how much of p it read.
-func Parse(p []byte) (Message, int) {
-	var message Message
+// ParseInto parses an IRC message from p into m, reusing m's Tags map
+// and Params slice rather than allocating fresh ones on every call,
+// and returns how much of p it read. The caller owns m between calls
+// and may reuse the same Message across an entire stream of reads,
+// e.g. from a Decoder, to avoid per-message allocation when parsing a
+// high volume of traffic.
+func ParseInto(p []byte, m *Message) (int, error) {
+	if m.Tags == nil {
+		m.Tags = Tags{}
+	} else {
+		for k := range m.Tags {
+			delete(m.Tags, k)
+		}
+	}
+	if m.Params == nil {
+		m.Params = []string{}
+	} else {
+		m.Params = m.Params[:0]
+	}
+
 	i, j := 0, 0
-	message.Tags, j = parseTags(p[i:])
+	j = parseTagsInto(p[i:], m.Tags)
 	i += j
-	message.Prefix, j = parsePrefix(p[i:])
+	m.Prefix, j = parsePrefix(p[i:])
 	i += j
-	message.Command, j = parseCommand(p[i:])
+	m.Command, j = parseCommand(p[i:])
 	i += j
-	message.Params, j = parseParams(p[i:])
+	m.Params, j = parseParamsInto(p[i:], m.Params)
 	i += j
 	if len(p)-i >= 2 && p[i+0] == '\r' && p[i+1] == '\n' {
 		i += 2
 	}
-	return message, i
+	return i, nil
+}
+
+// Parse parses an IRC message from p and returns it,
+// along with how much of p it read.
+func Parse(p []byte) (Message, int) {
+	var message Message
+	n, _ := ParseInto(p, &message)
+	return message, n
 }
 
 // ParseString converts s to a byte slice and calls Parse.
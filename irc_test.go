@@ -13,7 +13,7 @@ var splitTests = []struct {
 	ExpectedRest    string
 	ExpectedTags    Tags
 	ExpectedPrefix  Prefix
-	ExpectedCommand string
+	ExpectedCommand Command
 	ExpectedParams  []string
 }{
 	{"foo bar baz asdf", "",
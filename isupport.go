@@ -0,0 +1,52 @@
+package irc
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ParseISUPPORT decodes the KEY or KEY=VALUE tokens carried by the
+// params of an RPL_ISUPPORT (005) message into a map, excluding the
+// leading target nick and the trailing "are supported by this
+// server" text. Values using the \xHH escape form (e.g. \x20 for a
+// literal space) are unescaped.
+func ParseISUPPORT(params []string) map[string]string {
+	tokens := map[string]string{}
+	if len(params) <= 2 {
+		return tokens
+	}
+
+	for _, param := range params[1 : len(params)-1] {
+		key, value := param, ""
+		if i := strings.IndexByte(param, '='); i >= 0 {
+			key, value = param[:i], param[i+1:]
+		}
+		tokens[key] = unescapeISUPPORT(value)
+	}
+
+	return tokens
+}
+
+func unescapeISUPPORT(s string) string {
+	var out []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if n, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				if out == nil {
+					out = []byte(s[:i])
+				}
+				out = append(out, byte(n))
+				i += 3
+				continue
+			}
+		}
+		if out != nil {
+			out = append(out, s[i])
+		}
+	}
+
+	if out == nil {
+		return s
+	}
+	return string(out)
+}
@@ -0,0 +1,31 @@
+package irc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseISUPPORT(t *testing.T) {
+	t.Parallel()
+	m := ParseString(":irc.example.com 005 nick CHANTYPES=# PREFIX=(ov)@+ " +
+		"NETWORK=Example\\x20Net :are supported by this server\r\n")
+
+	got := ParseISUPPORT(m.Params)
+	want := map[string]string{
+		"CHANTYPES": "#",
+		"PREFIX":    "(ov)@+",
+		"NETWORK":   "Example Net",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestParseISUPPORTValuelessToken(t *testing.T) {
+	t.Parallel()
+	got := ParseISUPPORT([]string{"nick", "EXCEPTS", "are supported"})
+	want := map[string]string{"EXCEPTS": ""}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %#v, got %#v", want, got)
+	}
+}
@@ -0,0 +1,32 @@
+package irc
+
+import "testing"
+
+func BenchmarkParse(b *testing.B) {
+	for _, test := range splitTests {
+		test := test
+		b.Run(test.Input, func(b *testing.B) {
+			input := []byte(test.Input)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				Parse(input)
+			}
+		})
+	}
+}
+
+func BenchmarkParseInto(b *testing.B) {
+	for _, test := range splitTests {
+		test := test
+		b.Run(test.Input, func(b *testing.B) {
+			input := []byte(test.Input)
+			var m Message
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ParseInto(input, &m)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package irc
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseIntoMatchesParse(t *testing.T) {
+	t.Parallel()
+	for _, test := range splitTests {
+		test := test
+		t.Run(test.Input, func(t *testing.T) {
+			t.Parallel()
+			want, _ := Parse([]byte(test.Input))
+
+			var got Message
+			if _, err := ParseInto([]byte(test.Input), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %#v, got %#v", want, got)
+			}
+		})
+	}
+}
+
+func TestParseIntoReusesStorage(t *testing.T) {
+	t.Parallel()
+	var m Message
+	if _, err := ParseInto([]byte("@a=1;b=2 :src PRIVMSG #chan :hi"), &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tags, params := m.Tags, m.Params[:cap(m.Params)]
+
+	if _, err := ParseInto([]byte("FOO bar baz"), &m); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(m.Tags) != 0 {
+		t.Fatalf("expected tags to be cleared, got %#v", m.Tags)
+	}
+	if reflect.ValueOf(m.Tags).Pointer() != reflect.ValueOf(tags).Pointer() {
+		t.Fatal("expected Tags map to be reused, not reallocated")
+	}
+	if reflect.ValueOf(m.Params[:cap(m.Params)]).Pointer() != reflect.ValueOf(params).Pointer() {
+		t.Fatal("expected Params backing array to be reused, not reallocated")
+	}
+}
@@ -0,0 +1,24 @@
+package irc
+
+import (
+	"strings"
+	"sync"
+)
+
+// tagValueBuilderPool holds strings.Builders reused by ParseInto's
+// escape-processing slow paths (tag values and mixed-case commands),
+// which are rare compared to the fast path of slicing directly into
+// the input.
+var tagValueBuilderPool = sync.Pool{
+	New: func() interface{} { return new(strings.Builder) },
+}
+
+func getTagValueBuilder() *strings.Builder {
+	b := tagValueBuilderPool.Get().(*strings.Builder)
+	b.Reset()
+	return b
+}
+
+func putTagValueBuilder(b *strings.Builder) {
+	tagValueBuilderPool.Put(b)
+}
@@ -0,0 +1,62 @@
+package irc
+
+import "time"
+
+// TimeTagFormat is the layout used by the IRCv3 server-time "time"
+// tag: RFC3339 with millisecond precision and a literal "Z" for UTC.
+const TimeTagFormat = "2006-01-02T15:04:05.000Z"
+
+// Time returns the value of the "time" tag, as set by the
+// server-time capability.
+func (t Tags) Time() (time.Time, bool) {
+	v, ok := t["time"]
+	if !ok {
+		return time.Time{}, false
+	}
+	tm, err := time.Parse(TimeTagFormat, v)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return tm, true
+}
+
+// SetTime sets the "time" tag to tm, formatted per TimeTagFormat.
+func (t Tags) SetTime(tm time.Time) {
+	t["time"] = tm.UTC().Format(TimeTagFormat)
+}
+
+// MsgID returns the value of the "msgid" tag.
+func (t Tags) MsgID() (string, bool) {
+	v, ok := t["msgid"]
+	return v, ok
+}
+
+// SetMsgID sets the "msgid" tag.
+func (t Tags) SetMsgID(id string) {
+	t["msgid"] = id
+}
+
+// Label returns the value of the "label" client tag used by the
+// labeled-response capability.
+func (t Tags) Label() (string, bool) {
+	v, ok := t["label"]
+	return v, ok
+}
+
+// SetLabel sets the "label" client tag.
+func (t Tags) SetLabel(label string) {
+	t["label"] = label
+}
+
+// Batch returns the value of the "batch" tag, identifying which batch
+// a message belongs to.
+func (t Tags) Batch() (string, bool) {
+	v, ok := t["batch"]
+	return v, ok
+}
+
+// SetBatch sets the "batch" tag to ref, the reference of the batch
+// this message belongs to.
+func (t Tags) SetBatch(ref string) {
+	t["batch"] = ref
+}
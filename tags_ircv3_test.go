@@ -0,0 +1,46 @@
+package irc
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTagsTime(t *testing.T) {
+	t.Parallel()
+	tags := Tags{}
+	want := time.Date(2026, 7, 26, 12, 0, 0, 500000000, time.UTC)
+	tags.SetTime(want)
+
+	got, ok := tags.Time()
+	if !ok {
+		t.Fatal("expected ok")
+	}
+	if !got.Equal(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestTagsTimeMissing(t *testing.T) {
+	t.Parallel()
+	if _, ok := (Tags{}).Time(); ok {
+		t.Fatal("expected !ok")
+	}
+}
+
+func TestTagsMsgIDLabelBatch(t *testing.T) {
+	t.Parallel()
+	tags := Tags{}
+	tags.SetMsgID("abc123")
+	tags.SetLabel("l1")
+	tags.SetBatch("ref1")
+
+	if v, ok := tags.MsgID(); !ok || v != "abc123" {
+		t.Fatalf("MsgID: got %q, %v", v, ok)
+	}
+	if v, ok := tags.Label(); !ok || v != "l1" {
+		t.Fatalf("Label: got %q, %v", v, ok)
+	}
+	if v, ok := tags.Batch(); !ok || v != "ref1" {
+		t.Fatalf("Batch: got %q, %v", v, ok)
+	}
+}